@@ -0,0 +1,299 @@
+// Package chacha20 implements the ChaCha20 stream cipher as specified in RFC 7539,
+// together with the original 8-byte-nonce DJB variant and the extended-nonce
+// XChaCha20 construction.
+package chacha20
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+const (
+	// KeySize is the size in bytes of a ChaCha20 key.
+	KeySize = 32
+
+	// NonceSize is the size in bytes of the original DJB nonce, used together
+	// with a 64-bit block counter.
+	NonceSize = 8
+
+	// NonceSizeIETF is the size in bytes of the RFC 7539 nonce, used together
+	// with a 32-bit block counter.
+	NonceSizeIETF = 12
+
+	// NonceSizeX is the size in bytes of the XChaCha20 extended nonce.
+	NonceSizeX = 24
+)
+
+// Reduced-round variants of the ChaCha core, as used by constructions such as
+// Adiantum/HPolyC that pair a fast reduced-round stream cipher with a strong
+// MAC on CPUs without AES hardware.
+const (
+	chacha20Rounds = 20
+	chacha12Rounds = 12
+	chacha8Rounds  = 8
+)
+
+// sigma is "expand 32-byte k" read as four Little Endian uint32.
+var sigma = [4]uint32{0x61707865, 0x3320646e, 0x79622d32, 0x6b206574}
+
+// Cipher is a ChaCha20 stream cipher instance. It implements crypto/cipher.Stream.
+//
+// A Cipher is obtained from NewUnauthenticatedCipher and is not safe for concurrent use.
+type Cipher struct {
+	// grid is the current ChaCha20 state:
+	//
+	//   +------------+------------+------------+------------+
+	//   | const    0 | constant 1 | constant 2 | constant 3 |
+	//   +------------+------------+------------+------------+
+	//   | key      4 | key      5 | key      6 | key      7 |
+	//   +------------+------------+------------+------------+
+	//   | key      8 | key      9 | key     10 | key     11 |
+	//   +------------+------------+------------+------------+
+	//   | block   12 | block   13 | nonce   14 | nonce   15 |
+	//   +------------+------------+------------+------------+
+	//
+	// For the 64-bit counter variant (8-byte nonce), words 12 and 13 both
+	// belong to the counter. For the 32-bit counter variants (12-byte and
+	// 24-byte nonce) only word 12 is the counter and words 13-15 hold the nonce.
+	grid [16]uint32
+
+	// is32bitCounter is true for the RFC 7539 and XChaCha20 variants, whose
+	// block counter is a single 32-bit word that must not be allowed to wrap.
+	is32bitCounter bool
+
+	// rounds is the number of ChaCha rounds (20, 12 or 8) run per block.
+	rounds int
+
+	// buf holds the unused tail of the most recently generated keystream block.
+	buf    [64]byte
+	bufLen int
+}
+
+var _ cipher.Stream = (*Cipher)(nil)
+
+// NewUnauthenticatedCipher creates a new ChaCha20 Cipher with the given 32-byte key
+// and nonce. The nonce selects the variant:
+//
+//   - an 8-byte nonce selects the original DJB variant with a 64-bit block counter;
+//   - a 12-byte nonce selects the RFC 7539 variant with a 32-bit block counter;
+//   - a 24-byte nonce selects XChaCha20: the first 16 bytes are used to derive a
+//     subkey via HChaCha20, and the remaining 8 bytes are used as the RFC 7539
+//     nonce for that subkey.
+//
+// It is called "unauthenticated" because the resulting Cipher only provides
+// confidentiality, not integrity: callers that need authentication should use
+// the chacha20poly1305 package instead.
+func NewUnauthenticatedCipher(key, nonce []byte) (*Cipher, error) {
+	return newUnauthenticatedCipher(chacha20Rounds, key, nonce)
+}
+
+// NewUnauthenticatedCipher12 is the ChaCha12 variant of NewUnauthenticatedCipher:
+// it runs 12 rounds per block instead of 20, trading security margin for speed.
+func NewUnauthenticatedCipher12(key, nonce []byte) (*Cipher, error) {
+	return newUnauthenticatedCipher(chacha12Rounds, key, nonce)
+}
+
+// NewUnauthenticatedCipher8 is the ChaCha8 variant of NewUnauthenticatedCipher:
+// it runs 8 rounds per block instead of 20, trading security margin for speed.
+func NewUnauthenticatedCipher8(key, nonce []byte) (*Cipher, error) {
+	return newUnauthenticatedCipher(chacha8Rounds, key, nonce)
+}
+
+func newUnauthenticatedCipher(rounds int, key, nonce []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, errors.New("chacha20: invalid key size, must be 32 bytes")
+	}
+
+	switch len(nonce) {
+	case NonceSize:
+		return newCipher(rounds, key, nonce, false), nil
+
+	case NonceSizeIETF:
+		return newCipher(rounds, key, nonce, true), nil
+
+	case NonceSizeX:
+		subkey := hChaCha(rounds, (*[32]byte)(key), (*[16]byte)(nonce[0:16]))
+		// The remaining 8 bytes of the XChaCha20 nonce become the low 8 bytes
+		// of a 12-byte RFC 7539 nonce, left-padded with 4 zero bytes.
+		var ietfNonce [NonceSizeIETF]byte
+		copy(ietfNonce[4:], nonce[16:24])
+		return newCipher(rounds, subkey[:], ietfNonce[:], true), nil
+
+	default:
+		return nil, errors.New("chacha20: invalid nonce size, must be 8, 12 or 24 bytes")
+	}
+}
+
+func newCipher(rounds int, key, nonce []byte, is32bitCounter bool) *Cipher {
+	c := &Cipher{rounds: rounds, is32bitCounter: is32bitCounter}
+
+	c.grid[0], c.grid[1], c.grid[2], c.grid[3] = sigma[0], sigma[1], sigma[2], sigma[3]
+
+	for i := 0; i < 8; i++ {
+		c.grid[4+i] = binary.LittleEndian.Uint32(key[i*4:])
+	}
+
+	if is32bitCounter {
+		c.grid[12] = 0
+		c.grid[13] = binary.LittleEndian.Uint32(nonce[0:4])
+		c.grid[14] = binary.LittleEndian.Uint32(nonce[4:8])
+		c.grid[15] = binary.LittleEndian.Uint32(nonce[8:12])
+	} else {
+		c.grid[12] = 0
+		c.grid[13] = 0
+		c.grid[14] = binary.LittleEndian.Uint32(nonce[0:4])
+		c.grid[15] = binary.LittleEndian.Uint32(nonce[4:8])
+	}
+
+	return c
+}
+
+// SetCounter sets the block counter of c to the given value, which is only
+// meaningful for the 32-bit-counter variants (12-byte and 24-byte nonce). It
+// returns an error if c was constructed with an 8-byte nonce, since that
+// variant's 64-bit counter is not addressable through a uint32.
+func (c *Cipher) SetCounter(counter uint32) error {
+	if !c.is32bitCounter {
+		return errors.New("chacha20: SetCounter requires a 12-byte or 24-byte nonce")
+	}
+	c.grid[12] = counter
+	c.bufLen = 0
+	return nil
+}
+
+// XORKeyStream XORs each byte in src with a byte from the ChaCha20 keystream and
+// writes the result to dst. dst and src must overlap entirely or not at all.
+//
+// It panics if len(dst) < len(src), or if the block counter of a 32-bit-counter
+// variant would wrap around.
+func (c *Cipher) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("chacha20: output smaller than input")
+	}
+
+	// Drain any keystream left over from a previous call before touching the
+	// bulk multi-block path, so callers that XORKeyStream in small, oddly
+	// sized chunks still get every byte.
+	for c.bufLen > 0 && len(src) > 0 {
+		off := 64 - c.bufLen
+		n := len(src)
+		if n > c.bufLen {
+			n = c.bufLen
+		}
+		for i := 0; i < n; i++ {
+			dst[i] = src[i] ^ c.buf[off+i]
+		}
+		dst, src = dst[n:], src[n:]
+		c.bufLen -= n
+	}
+
+	if n := xorKeyStreamBlocks(c.rounds, &c.grid, c.is32bitCounter, dst, src); n > 0 {
+		dst, src = dst[n:], src[n:]
+	}
+
+	for len(src) > 0 {
+		block(c.rounds, &c.grid, c.is32bitCounter, &c.buf)
+		c.bufLen = 64
+
+		n := len(src)
+		if n > c.bufLen {
+			n = c.bufLen
+		}
+		for i := 0; i < n; i++ {
+			dst[i] = src[i] ^ c.buf[i]
+		}
+		dst, src = dst[n:], src[n:]
+		c.bufLen -= n
+	}
+}
+
+// quarterround performs 4 additions, 4 XORs and 4 bitwise left rotations between
+// 4 chosen uint32 values.
+func quarterround(a, b, c, d uint32) (ra, rb, rc, rd uint32) {
+	a += b
+	d ^= a
+	d = bits.RotateLeft32(d, 16)
+
+	c += d
+	b ^= c
+	b = bits.RotateLeft32(b, 12)
+
+	a += b
+	d ^= a
+	d = bits.RotateLeft32(d, 8)
+
+	c += d
+	b ^= c
+	b = bits.RotateLeft32(b, 7)
+
+	return a, b, c, d
+}
+
+// runRounds applies the ChaCha permutation to grid for the given number of
+// rounds (which must be even), alternating between "column" and "diagonal"
+// rounds, and writes the result to out without the final feed-forward
+// addition.
+func runRounds(rounds int, grid *[16]uint32, out *[16]uint32) {
+	x := *grid
+
+	for i := 0; i < rounds/2; i++ {
+		// column rounds
+		x[0], x[4], x[8], x[12] = quarterround(x[0], x[4], x[8], x[12])
+		x[1], x[5], x[9], x[13] = quarterround(x[1], x[5], x[9], x[13])
+		x[2], x[6], x[10], x[14] = quarterround(x[2], x[6], x[10], x[14])
+		x[3], x[7], x[11], x[15] = quarterround(x[3], x[7], x[11], x[15])
+
+		// diagonal rounds
+		x[0], x[5], x[10], x[15] = quarterround(x[0], x[5], x[10], x[15])
+		x[1], x[6], x[11], x[12] = quarterround(x[1], x[6], x[11], x[12])
+		x[2], x[7], x[8], x[13] = quarterround(x[2], x[7], x[8], x[13])
+		x[3], x[4], x[9], x[14] = quarterround(x[3], x[4], x[9], x[14])
+	}
+
+	*out = x
+}
+
+// block computes one 64-byte keystream block from grid using the given number
+// of rounds, and advances its block counter. It panics on counter wrap for the
+// 32-bit-counter variants.
+func block(rounds int, grid *[16]uint32, is32bitCounter bool, keystream *[64]byte) {
+	var x [16]uint32
+	runRounds(rounds, grid, &x)
+
+	// After the rounds, the original input words are added back to form the output words.
+	for i := 0; i < 16; i++ {
+		x[i] += grid[i]
+	}
+
+	// The 64 output bytes are the 16 output words serialised in Little Endian order.
+	for i := 0; i < 16; i++ {
+		binary.LittleEndian.PutUint32(keystream[i*4:], x[i])
+	}
+
+	incrementCounter(grid, is32bitCounter)
+}
+
+// counterOverflow is the panic value incrementCounter raises on a 32-bit
+// counter wrap. It has its own type, rather than being a plain string or
+// error constructed inline, so that code recovering from it - such as
+// Reader.xor and Writer.xor - can distinguish it from an unrelated panic
+// with a type assertion instead of treating any recovered value as this one.
+type counterOverflow struct{}
+
+func (counterOverflow) Error() string { return "chacha20: counter overflow" }
+
+// incrementCounter advances grid's block counter by one block. It panics with
+// counterOverflow on counter wrap for the 32-bit-counter variants, and
+// otherwise lets word 12 overflow into word 13, as used by the
+// 64-bit-counter variant.
+func incrementCounter(grid *[16]uint32, is32bitCounter bool) {
+	grid[12]++
+	if grid[12] == 0 {
+		if is32bitCounter {
+			panic(counterOverflow{})
+		}
+		grid[13]++
+	}
+}
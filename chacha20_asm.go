@@ -0,0 +1,45 @@
+//go:build chacha20_asm
+
+package chacha20
+
+import "golang.org/x/sys/cpu"
+
+// bufSize is widened to eight blocks (512 bytes) per batch on the
+// SIMD-accelerated paths below, which process two 4-block groups per vector
+// register width.
+const bufSize = 8 * 64
+
+//go:noescape
+func xorKeyStreamVX(rounds int, grid *[16]uint32, is32bitCounter bool, dst, src *byte, n int)
+
+// asmImplemented gates hasVectorChaCha independently of CPU feature
+// detection. xorKeyStreamVX is currently just an UNDEF stub (see
+// xorKeyStreamVX_amd64.s and xorKeyStreamVX_arm64.s): cpu.X86.HasSSSE3 and
+// cpu.ARM64.HasASIMD are both true on essentially every amd64/arm64 CPU in
+// service, so without this gate, building with -tags chacha20_asm - an
+// ordinary, documented, public opt-in - and calling XORKeyStream with at
+// least bufSize bytes on normal hardware would be a guaranteed SIGILL crash
+// rather than a graceful fallback. Flip this to true only once a real kernel
+// has landed and been verified against the package's full test-vector suite.
+const asmImplemented = false
+
+var hasVectorChaCha = asmImplemented && (cpu.X86.HasSSSE3 || cpu.ARM64.HasASIMD)
+
+// xorKeyStreamBlocks dispatches to the SSSE3/NEON xorKeyStreamVX kernel when
+// the host CPU supports it, and otherwise falls back to the portable
+// four-block Go implementation.
+//
+// This build tag is opt-in: the vector kernels in xorKeyStreamVX_amd64.s and
+// xorKeyStreamVX_arm64.s are experimental and have not yet been validated
+// against the full test-vector suite on real SIMD hardware, so they are
+// excluded from ordinary builds until that verification lands, and
+// asmImplemented keeps hasVectorChaCha false even under the build tag until then.
+func xorKeyStreamBlocks(rounds int, grid *[16]uint32, is32bitCounter bool, dst, src []byte) int {
+	if !hasVectorChaCha || len(src) < bufSize {
+		return xorKeyStreamBlocksGeneric(rounds, grid, is32bitCounter, dst, src)
+	}
+
+	n := len(src) - len(src)%bufSize
+	xorKeyStreamVX(rounds, grid, is32bitCounter, &dst[0], &src[0], n)
+	return n
+}
@@ -0,0 +1,55 @@
+package chacha20
+
+import "testing"
+
+func benchmarkXORKeyStream(b *testing.B, size int) {
+	key := make([]byte, 32)
+	nonce := make([]byte, NonceSizeIETF)
+	c, err := NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	buf := make([]byte, size)
+	b.SetBytes(int64(size))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.XORKeyStream(buf, buf)
+	}
+}
+
+func BenchmarkXORKeyStream64(b *testing.B)  { benchmarkXORKeyStream(b, 64) }
+func BenchmarkXORKeyStream1K(b *testing.B)  { benchmarkXORKeyStream(b, 1024) }
+func BenchmarkXORKeyStream8K(b *testing.B)  { benchmarkXORKeyStream(b, 8192) }
+func BenchmarkXORKeyStream64K(b *testing.B) { benchmarkXORKeyStream(b, 65536) }
+
+// BenchmarkXORKeyStreamOneBlockAtATime measures the cost of generating the
+// same number of blocks as BenchmarkXORKeyStream64K, but one at a time
+// through block() directly - the pre-xorKeyStreamBlocksGeneric code path -
+// rather than through xorKeyStreamBlocksGeneric's four-way interleaving. It
+// is the fair baseline for that comparison: driving the same work through
+// repeated small Cipher.XORKeyStream calls instead would also measure
+// per-call dispatch overhead that has nothing to do with interleaving.
+//
+// Measured against this baseline, xorKeyStreamBlocksGeneric is good for
+// roughly 1.3x on a scalar amd64 core, not the >2x this request originally
+// asked for; see the doc comment on xorKeyStreamBlocksGeneric for why
+// instruction-level parallelism across independent scalar chains falls well
+// short of the throughput real SIMD lanes (xorKeyStreamVX) would give.
+func BenchmarkXORKeyStreamOneBlockAtATime(b *testing.B) {
+	key := make([]byte, 32)
+	nonce := make([]byte, NonceSizeIETF)
+	c, err := NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	const size = 65536
+	b.SetBytes(size)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for n := 0; n < size; n += 64 {
+			block(c.rounds, &c.grid, c.is32bitCounter, &c.buf)
+		}
+	}
+}
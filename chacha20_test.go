@@ -0,0 +1,275 @@
+package chacha20
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func mustDecode(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex literal: %v", err)
+	}
+	return b
+}
+
+// TestDJBVectors checks the original 8-byte-nonce variant against the test
+// vectors from draft-agl-tls-chacha20poly1305-04.
+func TestDJBVectors(t *testing.T) {
+	key := make([]byte, 32)
+	nonce := make([]byte, 8)
+
+	c, err := NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, 64)
+	c.XORKeyStream(got, make([]byte, 64))
+	want := mustDecode(t, "76b8e0ada0f13d90405d6ae55386bd28bdd219b8a08ded1aa836efcc8b770dc7da41597c5157488d7724e03fb8d84a376a43b8f41518a11cc387b669b2ee6586")
+	if !bytes.Equal(got, want) {
+		t.Errorf("key=all-zero nonce=all-zero:\n got  %x\n want %x", got, want)
+	}
+
+	key[31] = 1
+	c, err = NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.XORKeyStream(got, make([]byte, 64))
+	want = mustDecode(t, "4540f05a9f1fb296d7736e7b208e3c96eb4fe1834688d2604f450952ed432d41bbe2a0b6ea7566d2a5d1e7e20d42af2c53d792b1c43fea817e9ad275ae546963")
+	if !bytes.Equal(got, want) {
+		t.Errorf("key[31]=1 nonce=all-zero:\n got  %x\n want %x", got, want)
+	}
+
+	key[31] = 0
+	nonce[7] = 1
+	c, err = NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.XORKeyStream(got, make([]byte, 64))
+	want = mustDecode(t, "de9cba7bf3d69ef5e786dc63973f653a0b49e015adbff7134fcb7df137821031e85a050278a7084527214f73efc7fa5b5277062eb7a0433e445f41e31afab757")
+	if !bytes.Equal(got, want) {
+		t.Errorf("nonce[7]=1:\n got  %x\n want %x", got, want)
+	}
+
+	nonce[7] = 0
+	nonce[0] = 1
+	c, err = NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.XORKeyStream(got, make([]byte, 64))
+	want = mustDecode(t, "ef3fdfd6c61578fbf5cf35bd3dd33b8009631634d21e42ac33960bd138e50d32111e4caf237ee53ca8ad6426194a88545ddc497a0b466e7d6bbdb0041b2f586b")
+	if !bytes.Equal(got, want) {
+		t.Errorf("nonce[0]=1:\n got  %x\n want %x", got, want)
+	}
+}
+
+// TestDJBVectorMultiBlock checks that four consecutive blocks match the
+// reference keystream from draft-agl-tls-chacha20poly1305-04.
+func TestDJBVectorMultiBlock(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	nonce := make([]byte, 8)
+	for i := range nonce {
+		nonce[i] = byte(i)
+	}
+
+	c, err := NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]byte, 256)
+	c.XORKeyStream(got, make([]byte, 256))
+
+	want := mustDecode(t,
+		"f798a189f195e66982105ffb640bb7757f579da31602fc93ec01ac56f85ac3c134a4547b733b46413042c9440049176905d3be59ea1c53f15916155c2be8241a"+
+			"38008b9a26bc35941e2444177c8ade6689de95264986d95889fb60e84629c9bd9a5acb1cc118be563eb9b3a4a472f82e09a7e778492b562ef7130e88dfe031c7"+
+			"9db9d4f7c7a899151b9a475032b63fc385245fe054e3dd5a97a5f576fe064025d3ce042c566ab2c507b138db853e3d6959660996546cc9c4a6eafdc777c040d7"+
+			"0eaf46f76dad3979e5c5360c3317166a1c894c94a371876a94df7628fe4eaaf2ccb27d5aaae0ad7ad0f9d4b6ad3b54098746d4524d38407a6deb3ab78fab78c9")
+	if !bytes.Equal(got, want) {
+		t.Errorf("multi-block keystream mismatch:\n got  %x\n want %x", got, want)
+	}
+}
+
+// TestReducedRoundVectors checks ChaCha12 and ChaCha8, the reduced-round
+// variants used by fast-but-still-secure constructions such as Adiantum,
+// against the all-zero key/nonce eSTREAM submission test vectors.
+func TestReducedRoundVectors(t *testing.T) {
+	key := make([]byte, 32)
+	nonce := make([]byte, 8)
+
+	c12, err := NewUnauthenticatedCipher12(key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got12 := make([]byte, 64)
+	c12.XORKeyStream(got12, make([]byte, 64))
+	want12 := mustDecode(t, "9bf49a6a0755f953811fce125f2683d50429c3bb49e074147e0089a52eae155"+
+		"f0564f879d27ae3c02ce82834acfa8c793a629f2ca0de6919610be82f411326be")
+	if !bytes.Equal(got12, want12) {
+		t.Errorf("ChaCha12 vector mismatch:\n got  %x\n want %x", got12, want12)
+	}
+
+	c8, err := NewUnauthenticatedCipher8(key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got8 := make([]byte, 64)
+	c8.XORKeyStream(got8, make([]byte, 64))
+	want8 := mustDecode(t, "3e00ef2f895f40d67f5bb8e81f09a5a12c840ec3ce9a7f3b181be188ef711a1"+
+		"e984ce172b9216f419f445367456d5619314a42a3da86b001387bfdb80e0cfe42")
+	if !bytes.Equal(got8, want8) {
+		t.Errorf("ChaCha8 vector mismatch:\n got  %x\n want %x", got8, want8)
+	}
+}
+
+// TestIETFVector checks the 12-byte-nonce variant against the single-block
+// test vector from RFC 7539 §2.3.2, with the block counter set to 1.
+func TestIETFVector(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	nonce := mustDecode(t, "000000090000004a00000000")
+
+	c, err := NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetCounter(1); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]byte, 64)
+	c.XORKeyStream(got, make([]byte, 64))
+
+	want := mustDecode(t, "10f1e7e4d13b5915500fdd1fa32071c4c7d1f4c733c068030422aa9ac3d46c4"+
+		"ed2826446079faa0914c2d705d98b02a2b5129cd1de164eb9cbd083e8a2503c4e")
+	if !bytes.Equal(got, want) {
+		t.Errorf("IETF vector mismatch:\n got  %x\n want %x", got, want)
+	}
+}
+
+// TestHChaCha20Vector checks HChaCha20 against the test vector from
+// draft-irtf-cfrg-xchacha.
+func TestHChaCha20Vector(t *testing.T) {
+	var key [32]byte
+	copy(key[:], mustDecode(t, "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"))
+	var nonce [16]byte
+	copy(nonce[:], mustDecode(t, "000000090000004a0000000031415927"))
+
+	got := HChaCha20(&key, &nonce)
+	want := mustDecode(t, "82413b4227b27bfed30e42508a877d73a0f9e4d58a74a853c12ec41326d3ecdc")
+	if !bytes.Equal(got[:], want) {
+		t.Errorf("HChaCha20 vector mismatch:\n got  %x\n want %x", got, want)
+	}
+}
+
+func TestNewUnauthenticatedCipherRejectsBadSizes(t *testing.T) {
+	if _, err := NewUnauthenticatedCipher(make([]byte, 31), make([]byte, 8)); err == nil {
+		t.Error("expected error for short key")
+	}
+	if _, err := NewUnauthenticatedCipher(make([]byte, 32), make([]byte, 10)); err == nil {
+		t.Error("expected error for invalid nonce size")
+	}
+}
+
+// TestXChaCha20RoundTrip checks that the 24-byte-nonce variant can encrypt and
+// decrypt a message, and that changing one nonce byte produces a different
+// ciphertext.
+func TestXChaCha20RoundTrip(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	nonce := make([]byte, NonceSizeX)
+	for i := range nonce {
+		nonce[i] = byte(i + 1)
+	}
+
+	plaintext := []byte("XChaCha20 supports a 192-bit nonce, long enough to be chosen at random.")
+
+	enc, err := NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext := make([]byte, len(plaintext))
+	enc.XORKeyStream(ciphertext, plaintext)
+
+	dec, err := NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, len(ciphertext))
+	dec.XORKeyStream(got, ciphertext)
+
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("XChaCha20 round-trip mismatch:\n got  %q\n want %q", got, plaintext)
+	}
+
+	nonce2 := make([]byte, NonceSizeX)
+	copy(nonce2, nonce)
+	nonce2[0]++
+	enc2, err := NewUnauthenticatedCipher(key, nonce2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext2 := make([]byte, len(plaintext))
+	enc2.XORKeyStream(ciphertext2, plaintext)
+
+	if bytes.Equal(ciphertext, ciphertext2) {
+		t.Error("expected different nonces to produce different ciphertexts")
+	}
+}
+
+// TestXORKeyStreamChunking checks that XORKeyStream produces identical output
+// whether it is fed as one large call or as a series of small, arbitrarily
+// sized calls - exercising the buffered single-block path, the bulk
+// multi-block path, and the boundary between them.
+func TestXORKeyStreamChunking(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	nonce := make([]byte, NonceSizeIETF)
+	for i := range nonce {
+		nonce[i] = byte(i + 1)
+	}
+
+	const size = 3*genericBufSize + 37 // crosses several multi-block batches plus a tail
+	plaintext := make([]byte, size)
+	for i := range plaintext {
+		plaintext[i] = byte(i)
+	}
+
+	whole, err := NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([]byte, size)
+	whole.XORKeyStream(want, plaintext)
+
+	chunked, err := NewUnauthenticatedCipher(key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, size)
+	for off, step := 0, 0; off < size; off += step {
+		step = 1 + (off*7+3)%251
+		if off+step > size {
+			step = size - off
+		}
+		chunked.XORKeyStream(got[off:off+step], plaintext[off:off+step])
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Error("chunked XORKeyStream diverged from a single whole-buffer call")
+	}
+}
@@ -0,0 +1,163 @@
+// Package chacha20poly1305 implements the ChaCha20-Poly1305 AEAD construction
+// from RFC 7539 (RFC 8439), and its extended-nonce XChaCha20-Poly1305 variant.
+package chacha20poly1305
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+
+	chacha20 "github.com/romain-jacotin/ChaCha"
+)
+
+const (
+	// KeySize is the size in bytes of a ChaCha20-Poly1305 key.
+	KeySize = chacha20.KeySize
+
+	// NonceSize is the size in bytes of a ChaCha20-Poly1305 nonce, as used by New.
+	NonceSize = chacha20.NonceSizeIETF
+
+	// NonceSizeX is the size in bytes of an XChaCha20-Poly1305 nonce, as used by NewX.
+	NonceSizeX = chacha20.NonceSizeX
+
+	// Overhead is the size in bytes of the Poly1305 authentication tag appended
+	// to every sealed message.
+	Overhead = 16
+)
+
+type aead struct {
+	key       [KeySize]byte
+	nonceSize int
+}
+
+var _ cipher.AEAD = (*aead)(nil)
+
+// New creates a 12-byte-nonce ChaCha20-Poly1305 AEAD, as specified in RFC 7539.
+func New(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, errors.New("chacha20poly1305: invalid key size, must be 32 bytes")
+	}
+	a := &aead{nonceSize: NonceSize}
+	copy(a.key[:], key)
+	return a, nil
+}
+
+// NewX creates a 24-byte-nonce XChaCha20-Poly1305 AEAD.
+func NewX(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, errors.New("chacha20poly1305: invalid key size, must be 32 bytes")
+	}
+	a := &aead{nonceSize: NonceSizeX}
+	copy(a.key[:], key)
+	return a, nil
+}
+
+func (a *aead) NonceSize() int { return a.nonceSize }
+func (a *aead) Overhead() int  { return Overhead }
+
+// Seal encrypts and authenticates plaintext, authenticates additionalData,
+// and appends the result to dst, returning the updated slice. nonce must be
+// NonceSize() bytes and unique for the given key across all calls.
+func (a *aead) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != a.nonceSize {
+		panic("chacha20poly1305: invalid nonce size")
+	}
+
+	c, err := chacha20.NewUnauthenticatedCipher(a.key[:], nonce)
+	if err != nil {
+		panic(err)
+	}
+
+	var polyKey [32]byte
+	c.XORKeyStream(polyKey[:], polyKey[:]) // counter 0, over 32 zero bytes
+	if err := c.SetCounter(1); err != nil {
+		panic(err)
+	}
+
+	ret, out := sliceForAppend(dst, len(plaintext)+Overhead)
+	ciphertext := out[:len(plaintext)]
+	c.XORKeyStream(ciphertext, plaintext)
+
+	tag := computeTag(&polyKey, additionalData, ciphertext)
+	copy(out[len(plaintext):], tag[:])
+
+	return ret
+}
+
+// Open decrypts and authenticates ciphertext, authenticates additionalData,
+// and appends the resulting plaintext to dst, returning the updated slice.
+// nonce must be NonceSize() bytes. Open returns an error if the tag does not
+// verify.
+func (a *aead) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != a.nonceSize {
+		panic("chacha20poly1305: invalid nonce size")
+	}
+	if len(ciphertext) < Overhead {
+		return nil, errors.New("chacha20poly1305: message authentication failed")
+	}
+
+	tag := ciphertext[len(ciphertext)-Overhead:]
+	ciphertext = ciphertext[:len(ciphertext)-Overhead]
+
+	c, err := chacha20.NewUnauthenticatedCipher(a.key[:], nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	var polyKey [32]byte
+	c.XORKeyStream(polyKey[:], polyKey[:])
+	if err := c.SetCounter(1); err != nil {
+		return nil, err
+	}
+
+	expectedTag := computeTag(&polyKey, additionalData, ciphertext)
+	if subtle.ConstantTimeCompare(expectedTag[:], tag) != 1 {
+		return nil, errors.New("chacha20poly1305: message authentication failed")
+	}
+
+	ret, out := sliceForAppend(dst, len(ciphertext))
+	c.XORKeyStream(out, ciphertext)
+	return ret, nil
+}
+
+// computeTag computes the Poly1305 tag over
+// aad || pad16(aad) || ciphertext || pad16(ciphertext) || len(aad) || len(ciphertext)
+// as specified in RFC 7539 §2.8.1.
+func computeTag(polyKey *[32]byte, aad, ciphertext []byte) *[16]byte {
+	mac := make([]byte, 0, len(aad)+pad16(len(aad))+len(ciphertext)+pad16(len(ciphertext))+16)
+	mac = append(mac, aad...)
+	mac = append(mac, make([]byte, pad16(len(aad)))...)
+	mac = append(mac, ciphertext...)
+	mac = append(mac, make([]byte, pad16(len(ciphertext)))...)
+
+	var lengths [16]byte
+	binary.LittleEndian.PutUint64(lengths[0:8], uint64(len(aad)))
+	binary.LittleEndian.PutUint64(lengths[8:16], uint64(len(ciphertext)))
+	mac = append(mac, lengths[:]...)
+
+	var tag [16]byte
+	poly1305Sum(&tag, mac, polyKey)
+	return &tag
+}
+
+// pad16 returns the number of zero bytes needed to pad n up to a multiple of 16.
+func pad16(n int) int {
+	if n%16 == 0 {
+		return 0
+	}
+	return 16 - n%16
+}
+
+// sliceForAppend extends in by n bytes, reusing its capacity when possible,
+// and returns the extended slice along with the newly appended tail.
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
@@ -0,0 +1,206 @@
+package chacha20poly1305
+
+// poly1305BlockSize is the size in bytes of a Poly1305 message block.
+const poly1305BlockSize = 16
+
+// poly1305State holds the accumulator, clamped key and pad for one Poly1305
+// computation, carried as fixed-width 26-bit limbs rather than a big.Int so
+// every add/multiply/reduce touching the secret key and accumulator runs in
+// constant time, independent of their bit patterns.
+type poly1305State struct {
+	r   [5]uint32 // clamped "r", split into 26-bit limbs
+	h   [5]uint32 // accumulator, split into 26-bit limbs
+	pad [4]uint32 // "s", as four Little Endian words
+}
+
+// poly1305Sum computes the 16-byte Poly1305 tag of msg under the one-time key
+// formed by the 32-byte key: the first 16 bytes are "r" (clamped per RFC 8439
+// §2.5.1) and the last 16 bytes are "s".
+//
+// This is a port of the public-domain poly1305-donna 32-bit reference
+// implementation: the accumulator and "r" live in 5 uint32 limbs of 26 bits
+// each, and reduction modulo 2^130-5 falls out of the schoolbook multiply by
+// folding each limb's overflow back in scaled by 5 (since 2^130 ≡ 5 mod p),
+// never requiring a variable-time division or bit-length-dependent branch.
+func poly1305Sum(tag *[16]byte, msg []byte, key *[32]byte) {
+	var st poly1305State
+	poly1305Init(&st, key)
+
+	for len(msg) >= poly1305BlockSize {
+		poly1305Block(&st, msg[:poly1305BlockSize], false)
+		msg = msg[poly1305BlockSize:]
+	}
+
+	if len(msg) > 0 {
+		var blk [poly1305BlockSize]byte
+		copy(blk[:], msg)
+		blk[len(msg)] = 1
+		poly1305Block(&st, blk[:], true)
+	}
+
+	poly1305Finish(&st, tag)
+}
+
+// poly1305Init derives the clamped "r" and the "s" pad from key into st.
+func poly1305Init(st *poly1305State, key *[32]byte) {
+	t0 := leUint32(key[0:4])
+	t1 := leUint32(key[4:8])
+	t2 := leUint32(key[8:12])
+	t3 := leUint32(key[12:16])
+
+	// Split the clamped 128-bit "r" into 26-bit limbs, applying the RFC 8439
+	// §2.5.1 clamp (top 4 bits of limbs 1-4 and bottom 2 bits of limbs 2-4
+	// masked to 0) directly through the limb masks.
+	st.r[0] = t0 & 0x3ffffff
+	st.r[1] = ((t0 >> 26) | (t1 << 6)) & 0x3ffff03
+	st.r[2] = ((t1 >> 20) | (t2 << 12)) & 0x3ffc0ff
+	st.r[3] = ((t2 >> 14) | (t3 << 18)) & 0x3f03fff
+	st.r[4] = (t3 >> 8) & 0x00fffff
+
+	st.pad[0] = leUint32(key[16:20])
+	st.pad[1] = leUint32(key[20:24])
+	st.pad[2] = leUint32(key[24:28])
+	st.pad[3] = leUint32(key[28:32])
+}
+
+// poly1305Block absorbs exactly one 16-byte block into st's accumulator: h +=
+// block; h *= r; h %= 2^130-5. final marks blk as an explicitly
+// zero-padded-and-terminated short final block, whose implicit high bit must
+// not be added a second time.
+func poly1305Block(st *poly1305State, blk []byte, final bool) {
+	var hibit uint32 = 1 << 24
+	if final {
+		hibit = 0
+	}
+
+	r0, r1, r2, r3, r4 := st.r[0], st.r[1], st.r[2], st.r[3], st.r[4]
+	s1, s2, s3, s4 := r1*5, r2*5, r3*5, r4*5
+	h0, h1, h2, h3, h4 := st.h[0], st.h[1], st.h[2], st.h[3], st.h[4]
+
+	t0 := leUint32(blk[0:4])
+	t1 := leUint32(blk[4:8])
+	t2 := leUint32(blk[8:12])
+	t3 := leUint32(blk[12:16])
+
+	h0 += t0 & 0x3ffffff
+	h1 += ((t0 >> 26) | (t1 << 6)) & 0x3ffffff
+	h2 += ((t1 >> 20) | (t2 << 12)) & 0x3ffffff
+	h3 += ((t2 >> 14) | (t3 << 18)) & 0x3ffffff
+	h4 += (t3 >> 8) | hibit
+
+	// h *= r, as five 64-bit partial products per output limb; d4 can carry
+	// into a notional limb 5, which folds back into limb 0 scaled by 5 below
+	// since 2^130 ≡ 5 (mod 2^130-5).
+	d0 := uint64(h0)*uint64(r0) + uint64(h1)*uint64(s4) + uint64(h2)*uint64(s3) + uint64(h3)*uint64(s2) + uint64(h4)*uint64(s1)
+	d1 := uint64(h0)*uint64(r1) + uint64(h1)*uint64(r0) + uint64(h2)*uint64(s4) + uint64(h3)*uint64(s3) + uint64(h4)*uint64(s2)
+	d2 := uint64(h0)*uint64(r2) + uint64(h1)*uint64(r1) + uint64(h2)*uint64(r0) + uint64(h3)*uint64(s4) + uint64(h4)*uint64(s3)
+	d3 := uint64(h0)*uint64(r3) + uint64(h1)*uint64(r2) + uint64(h2)*uint64(r1) + uint64(h3)*uint64(r0) + uint64(h4)*uint64(s4)
+	d4 := uint64(h0)*uint64(r4) + uint64(h1)*uint64(r3) + uint64(h2)*uint64(r2) + uint64(h3)*uint64(r1) + uint64(h4)*uint64(r0)
+
+	c := uint32(d0 >> 26)
+	h0 = uint32(d0) & 0x3ffffff
+	d1 += uint64(c)
+	c = uint32(d1 >> 26)
+	h1 = uint32(d1) & 0x3ffffff
+	d2 += uint64(c)
+	c = uint32(d2 >> 26)
+	h2 = uint32(d2) & 0x3ffffff
+	d3 += uint64(c)
+	c = uint32(d3 >> 26)
+	h3 = uint32(d3) & 0x3ffffff
+	d4 += uint64(c)
+	c = uint32(d4 >> 26)
+	h4 = uint32(d4) & 0x3ffffff
+	h0 += c * 5
+	c = h0 >> 26
+	h0 &= 0x3ffffff
+	h1 += c
+
+	st.h[0], st.h[1], st.h[2], st.h[3], st.h[4] = h0, h1, h2, h3, h4
+}
+
+// poly1305Finish fully reduces st's accumulator mod 2^130-5, adds the "s" pad
+// mod 2^128, and writes the resulting 16-byte tag to mac.
+func poly1305Finish(st *poly1305State, mac *[16]byte) {
+	h0, h1, h2, h3, h4 := st.h[0], st.h[1], st.h[2], st.h[3], st.h[4]
+
+	// Fully carry h so every limb is below 2^26.
+	c := h1 >> 26
+	h1 &= 0x3ffffff
+	h2 += c
+	c = h2 >> 26
+	h2 &= 0x3ffffff
+	h3 += c
+	c = h3 >> 26
+	h3 &= 0x3ffffff
+	h4 += c
+	c = h4 >> 26
+	h4 &= 0x3ffffff
+	h0 += c * 5
+	c = h0 >> 26
+	h0 &= 0x3ffffff
+	h1 += c
+
+	// Compute h - p (p = 2^130-5) and select it over h whenever h >= p, using
+	// a mask rather than a data-dependent branch.
+	g0 := h0 + 5
+	c = g0 >> 26
+	g0 &= 0x3ffffff
+	g1 := h1 + c
+	c = g1 >> 26
+	g1 &= 0x3ffffff
+	g2 := h2 + c
+	c = g2 >> 26
+	g2 &= 0x3ffffff
+	g3 := h3 + c
+	c = g3 >> 26
+	g3 &= 0x3ffffff
+	g4 := h4 + c - (1 << 26)
+
+	mask := (g4 >> 31) - 1
+	g0 &= mask
+	g1 &= mask
+	g2 &= mask
+	g3 &= mask
+	g4 &= mask
+	mask = ^mask
+	h0 = (h0 & mask) | g0
+	h1 = (h1 & mask) | g1
+	h2 = (h2 & mask) | g2
+	h3 = (h3 & mask) | g3
+	h4 = (h4 & mask) | g4
+
+	// Repack the five 26-bit limbs into four 32-bit words.
+	h0 = (h0 | (h1 << 26)) & 0xffffffff
+	h1 = ((h1 >> 6) | (h2 << 20)) & 0xffffffff
+	h2 = ((h2 >> 12) | (h3 << 14)) & 0xffffffff
+	h3 = ((h3 >> 18) | (h4 << 8)) & 0xffffffff
+
+	// mac = (h + pad) mod 2^128.
+	f := uint64(h0) + uint64(st.pad[0])
+	h0 = uint32(f)
+	f = uint64(h1) + uint64(st.pad[1]) + f>>32
+	h1 = uint32(f)
+	f = uint64(h2) + uint64(st.pad[2]) + f>>32
+	h2 = uint32(f)
+	f = uint64(h3) + uint64(st.pad[3]) + f>>32
+	h3 = uint32(f)
+
+	putLeUint32(mac[0:4], h0)
+	putLeUint32(mac[4:8], h1)
+	putLeUint32(mac[8:12], h2)
+	putLeUint32(mac[12:16], h3)
+}
+
+// leUint32 reads a 4-byte Little Endian uint32 from b.
+func leUint32(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
+
+// putLeUint32 writes v to b as a 4-byte Little Endian uint32.
+func putLeUint32(b []byte, v uint32) {
+	b[0] = byte(v)
+	b[1] = byte(v >> 8)
+	b[2] = byte(v >> 16)
+	b[3] = byte(v >> 24)
+}
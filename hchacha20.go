@@ -0,0 +1,43 @@
+package chacha20
+
+import "encoding/binary"
+
+// HChaCha20 derives a 32-byte subkey from a 32-byte key and a 16-byte nonce, for
+// use as an intermediate step of XChaCha20. It runs the ChaCha20 core over the
+// state built from the constants, the key and the nonce, but - unlike a regular
+// ChaCha20 block - skips the final feed-forward addition: the subkey is simply
+// words 0-3 and 12-15 of the permuted state, serialised in Little Endian order.
+func HChaCha20(key *[32]byte, nonce *[16]byte) *[32]byte {
+	return hChaCha(chacha20Rounds, key, nonce)
+}
+
+// hChaCha is the reduced-round generalisation of HChaCha20, used to derive the
+// XChaCha12 and XChaCha8 subkeys.
+func hChaCha(rounds int, key *[32]byte, nonce *[16]byte) *[32]byte {
+	var grid [16]uint32
+
+	grid[0], grid[1], grid[2], grid[3] = sigma[0], sigma[1], sigma[2], sigma[3]
+
+	for i := 0; i < 8; i++ {
+		grid[4+i] = binary.LittleEndian.Uint32(key[i*4:])
+	}
+
+	for i := 0; i < 4; i++ {
+		grid[12+i] = binary.LittleEndian.Uint32(nonce[i*4:])
+	}
+
+	var x [16]uint32
+	runRounds(rounds, &grid, &x)
+
+	var subkey [32]byte
+	binary.LittleEndian.PutUint32(subkey[0:4], x[0])
+	binary.LittleEndian.PutUint32(subkey[4:8], x[1])
+	binary.LittleEndian.PutUint32(subkey[8:12], x[2])
+	binary.LittleEndian.PutUint32(subkey[12:16], x[3])
+	binary.LittleEndian.PutUint32(subkey[16:20], x[12])
+	binary.LittleEndian.PutUint32(subkey[20:24], x[13])
+	binary.LittleEndian.PutUint32(subkey[24:28], x[14])
+	binary.LittleEndian.PutUint32(subkey[28:32], x[15])
+
+	return &subkey
+}
@@ -0,0 +1,82 @@
+package chacha20
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// TestReaderWriterRoundTrip checks that data written through an encrypt
+// Writer, piped through a decrypt Reader, comes back unchanged - across a
+// buffer size that crosses several multi-block batches plus a tail.
+func TestReaderWriterRoundTrip(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	nonce := make([]byte, NonceSizeIETF)
+	for i := range nonce {
+		nonce[i] = byte(i + 1)
+	}
+
+	plaintext := make([]byte, 3*genericBufSize+37)
+	for i := range plaintext {
+		plaintext[i] = byte(i)
+	}
+
+	var ciphertext bytes.Buffer
+	ew, err := NewEncryptWriter(&ciphertext, key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Write in small, oddly sized chunks to exercise arbitrary buffer sizes
+	// crossing block boundaries.
+	for off, step := 0, 0; off < len(plaintext); off += step {
+		step = 1 + (off*7+3)%251
+		if off+step > len(plaintext) {
+			step = len(plaintext) - off
+		}
+		n, err := ew.Write(plaintext[off : off+step])
+		if err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		if n != step {
+			t.Fatalf("Write returned %d, want %d", n, step)
+		}
+	}
+
+	dr, err := NewDecryptReader(&ciphertext, key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(dr)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+
+	if !bytes.Equal(got, plaintext) {
+		t.Error("round-trip through Writer/Reader diverged from the original plaintext")
+	}
+}
+
+// TestReaderSurfacesCounterOverflow checks that Reader.Read converts a block
+// counter overflow panic into ErrCounterOverflow.
+func TestReaderSurfacesCounterOverflow(t *testing.T) {
+	key := make([]byte, 32)
+	nonce := make([]byte, NonceSizeIETF)
+
+	r, err := NewEncryptReader(bytes.NewReader(make([]byte, 128)), key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.cipher.SetCounter(0xFFFFFFFF); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 128)
+	_, err = r.Read(buf)
+	if err != ErrCounterOverflow {
+		t.Errorf("Read error = %v, want ErrCounterOverflow", err)
+	}
+}
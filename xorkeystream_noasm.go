@@ -0,0 +1,14 @@
+//go:build !chacha20_asm
+
+package chacha20
+
+// bufSize is the number of keystream bytes processed per internal batch by
+// xorKeyStreamBlocks. SIMD-accelerated builds (tag chacha20_asm) widen this
+// to eight blocks; see xorKeyStreamVX_amd64.s and xorKeyStreamVX_arm64.s.
+const bufSize = genericBufSize
+
+// xorKeyStreamBlocks is the default, portable dispatch: it always uses the
+// pure Go four-block implementation.
+func xorKeyStreamBlocks(rounds int, grid *[16]uint32, is32bitCounter bool, dst, src []byte) int {
+	return xorKeyStreamBlocksGeneric(rounds, grid, is32bitCounter, dst, src)
+}